@@ -0,0 +1,115 @@
+// Package featureflag implements Prometheus' `--enable-feature` pattern
+// for Thanos components. Experimental behaviors are gated behind a
+// named feature instead of a dedicated flag so they can be iterated on,
+// documented and removed without a flag deprecation cycle.
+package featureflag
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+)
+
+// Feature names as accepted by `--enable-feature`. Not every component
+// understands every feature; a component ignores features it doesn't
+// implement rather than failing to start, mirroring Prometheus.
+const (
+	RemoteWrite20           = "remote-write-2.0"
+	NativeHistogramsIngest  = "native-histograms-ingest"
+	ExemplarForwarding      = "exemplar-forwarding"
+	QueryAtModifierPushdown = "query-at-modifier-pushdown"
+)
+
+// knownFeatures is used purely to reject typos early; it is not
+// per-component, so a name valid for one binary is accepted (and
+// ignored) by another.
+var knownFeatures = map[string]bool{
+	RemoteWrite20:           true,
+	NativeHistogramsIngest:  true,
+	ExemplarForwarding:      true,
+	QueryAtModifierPushdown: true,
+}
+
+// Flags holds the raw `--enable-feature` values until Parse resolves
+// them into a queryable Set.
+type Flags struct {
+	raw *[]string
+}
+
+// Register adds a repeatable `--enable-feature` flag to cmd and returns
+// a handle used to resolve it into a Set once the app has parsed flags.
+func Register(cmd *kingpin.CmdClause) *Flags {
+	f := &Flags{}
+	f.raw = cmd.Flag("enable-feature", "Comma separated experimental feature names to enable. The current list of features is described in the Thanos documentation.").Strings()
+	return f
+}
+
+// Set is the resolved, validated collection of enabled feature names
+// for a running process.
+type Set struct {
+	enabled map[string]bool
+}
+
+// Parse validates the registered --enable-feature values against the
+// known feature list, logs each enabled feature at startup and exports
+// a thanos_feature_enabled gauge for each so enabled features are
+// visible in metrics as well as logs.
+func (f *Flags) Parse(logger *slog.Logger, reg prometheus.Registerer) (*Set, error) {
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "thanos_feature_enabled",
+		Help: "1 if the experimental feature is enabled, 0 otherwise.",
+	}, []string{"name"})
+	if reg != nil {
+		reg.MustRegister(gauge)
+	}
+
+	s := &Set{enabled: make(map[string]bool, len(*f.raw))}
+	for name := range knownFeatures {
+		gauge.WithLabelValues(name).Set(0)
+	}
+
+	for _, raw := range *f.raw {
+		for _, name := range strings.Split(raw, ",") {
+			if !knownFeatures[name] {
+				return nil, errors.Errorf("unknown --enable-feature value %q", name)
+			}
+			s.enabled[name] = true
+			gauge.WithLabelValues(name).Set(1)
+			logger.Info("enabled experimental feature", "feature", name)
+		}
+	}
+	return s, nil
+}
+
+// Enabled reports whether the named feature was passed to
+// --enable-feature.
+func (s *Set) Enabled(name string) bool {
+	if s == nil {
+		return false
+	}
+	return s.enabled[name]
+}
+
+// RegisterStatusHTTP exposes the resolved feature set on
+// /status/features, matching the pattern of Prometheus' own feature
+// status page.
+func (s *Set) RegisterStatusHTTP(mux *http.ServeMux) {
+	mux.HandleFunc("/status/features", func(w http.ResponseWriter, r *http.Request) {
+		names := make([]string, 0, len(s.enabled))
+		for name := range s.enabled {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(struct {
+			Enabled []string `json:"enabled"`
+		}{Enabled: names})
+	})
+}
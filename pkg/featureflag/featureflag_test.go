@@ -0,0 +1,84 @@
+package featureflag
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	kingpin "gopkg.in/alecthomas/kingpin.v2"
+
+	"github.com/thanos-io/thanos/pkg/testutil"
+)
+
+func TestFlags_Parse(t *testing.T) {
+	app := kingpin.New("test", "")
+	cmd := app.Command("receive", "")
+	flags := Register(cmd)
+
+	_, err := app.Parse([]string{"receive", "--enable-feature", RemoteWrite20, "--enable-feature", ExemplarForwarding})
+	testutil.Ok(t, err)
+
+	set, err := flags.Parse(nopLogger(), prometheus.NewRegistry())
+	testutil.Ok(t, err)
+
+	testutil.Assert(t, set.Enabled(RemoteWrite20))
+	testutil.Assert(t, set.Enabled(ExemplarForwarding))
+	testutil.Assert(t, !set.Enabled(NativeHistogramsIngest))
+}
+
+func TestFlags_Parse_CommaSeparated(t *testing.T) {
+	app := kingpin.New("test", "")
+	cmd := app.Command("receive", "")
+	flags := Register(cmd)
+
+	raw := RemoteWrite20 + "," + ExemplarForwarding
+	_, err := app.Parse([]string{"receive", "--enable-feature", raw})
+	testutil.Ok(t, err)
+
+	set, err := flags.Parse(nopLogger(), prometheus.NewRegistry())
+	testutil.Ok(t, err)
+
+	testutil.Assert(t, set.Enabled(RemoteWrite20))
+	testutil.Assert(t, set.Enabled(ExemplarForwarding))
+	testutil.Assert(t, !set.Enabled(NativeHistogramsIngest))
+}
+
+func TestFlags_Parse_UnknownFeature(t *testing.T) {
+	app := kingpin.New("test", "")
+	cmd := app.Command("receive", "")
+	flags := Register(cmd)
+
+	_, err := app.Parse([]string{"receive", "--enable-feature", "not-a-real-feature"})
+	testutil.Ok(t, err)
+
+	_, err = flags.Parse(nopLogger(), nil)
+	testutil.NotOk(t, err)
+}
+
+func TestSet_RegisterStatusHTTP(t *testing.T) {
+	app := kingpin.New("test", "")
+	cmd := app.Command("query", "")
+	flags := Register(cmd)
+
+	_, err := app.Parse([]string{"query", "--enable-feature", QueryAtModifierPushdown})
+	testutil.Ok(t, err)
+
+	set, err := flags.Parse(nopLogger(), nil)
+	testutil.Ok(t, err)
+
+	mux := http.NewServeMux()
+	set.RegisterStatusHTTP(mux)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/status/features", nil))
+
+	testutil.Equals(t, http.StatusOK, rec.Code)
+	testutil.Assert(t, len(rec.Body.String()) > 0)
+}
+
+func nopLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
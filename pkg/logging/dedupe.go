@@ -0,0 +1,216 @@
+package logging
+
+import (
+	"container/list"
+	"context"
+	"hash/fnv"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultDedupWindow    = 10 * time.Second
+	defaultMaxTrackedKeys = 4096
+)
+
+// dedupState is the tracking table shared by a dedupingHandler and
+// every handler derived from it via WithAttrs/WithGroup. It must be
+// shared by pointer, not copied: slog calls WithAttrs/WithGroup to
+// produce per-call-site handlers that are then used concurrently, and
+// they all need to dedup against the same key space under the same
+// lock - one mutex per clone would let two goroutines touch the same
+// map/list through different locks.
+type dedupState struct {
+	mu      sync.Mutex
+	entries map[uint64]*dedupEntry
+	order   *list.List // front = most recently touched
+}
+
+// dedupingHandler suppresses repeat emissions of a record that shares
+// the same (level, message, sorted attrs) key as one already emitted
+// within window. The suppressed count is carried as a `repeated`
+// attribute on the key's next emission once window has passed, or on a
+// copy of the key's last suppressed record that is emitted when the key
+// is evicted from the bounded LRU, so bursty identical errors degrade to
+// one line instead of flooding the log stream, without ever growing
+// unbounded memory for a key space an attacker or flapping scrape
+// target could otherwise grow without limit.
+type dedupingHandler struct {
+	next   slog.Handler
+	window time.Duration
+	max    int
+	state  *dedupState
+
+	// boundAttrs are the attrs bound via WithAttrs on this handler (and
+	// its ancestors), keys already qualified by any enclosing
+	// WithGroup prefix. They fold into the dedup key alongside each
+	// record's call-site attrs: two requests logging the same message
+	// through loggers bound to different request_id/component attrs
+	// must not collide into the same key, or the second request's
+	// correlating attrs are the ones silently suppressed.
+	boundAttrs  []slog.Attr
+	groupPrefix string
+}
+
+type dedupEntry struct {
+	key        uint64
+	lastEmit   time.Time
+	suppressed int
+	elem       *list.Element
+	record     slog.Record // most recent occurrence, used to build the trailing summary on eviction.
+}
+
+// NewDedupingHandler wraps next so that records identical in (level,
+// message, attrs) within window are collapsed into one emission plus a
+// trailing `repeated=N` count. max bounds the number of distinct keys
+// tracked at once; the least-recently-touched key is evicted (emitting
+// its pending suppressed count first) once the bound is hit.
+func NewDedupingHandler(next slog.Handler, window time.Duration, max int) slog.Handler {
+	return &dedupingHandler{
+		next:   next,
+		window: window,
+		max:    max,
+		state: &dedupState{
+			entries: make(map[uint64]*dedupEntry),
+			order:   list.New(),
+		},
+	}
+}
+
+func (h *dedupingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *dedupingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	qualified := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		if h.groupPrefix != "" {
+			a.Key = h.groupPrefix + "." + a.Key
+		}
+		qualified[i] = a
+	}
+
+	bound := make([]slog.Attr, 0, len(h.boundAttrs)+len(qualified))
+	bound = append(bound, h.boundAttrs...)
+	bound = append(bound, qualified...)
+
+	return &dedupingHandler{
+		next:        h.next.WithAttrs(attrs),
+		window:      h.window,
+		max:         h.max,
+		state:       h.state,
+		boundAttrs:  bound,
+		groupPrefix: h.groupPrefix,
+	}
+}
+
+func (h *dedupingHandler) WithGroup(name string) slog.Handler {
+	prefix := name
+	if h.groupPrefix != "" {
+		prefix = h.groupPrefix + "." + name
+	}
+
+	return &dedupingHandler{
+		next:        h.next.WithGroup(name),
+		window:      h.window,
+		max:         h.max,
+		state:       h.state,
+		boundAttrs:  h.boundAttrs,
+		groupPrefix: prefix,
+	}
+}
+
+func (h *dedupingHandler) Handle(ctx context.Context, r slog.Record) error {
+	key := hashRecord(r, h.boundAttrs)
+
+	h.state.mu.Lock()
+	e, ok := h.state.entries[key]
+	if ok && time.Since(e.lastEmit) < h.window {
+		e.suppressed++
+		e.record = r.Clone()
+		h.state.order.MoveToFront(e.elem)
+		h.state.mu.Unlock()
+		return nil
+	}
+
+	var evicted []slog.Record
+	suppressed := 0
+	if ok {
+		suppressed = e.suppressed
+		e.suppressed = 0
+		e.lastEmit = time.Now()
+		e.record = r.Clone()
+		h.state.order.MoveToFront(e.elem)
+	} else {
+		e = &dedupEntry{key: key, lastEmit: time.Now(), record: r.Clone()}
+		e.elem = h.state.order.PushFront(key)
+		h.state.entries[key] = e
+		evicted = h.evictLocked()
+	}
+	h.state.mu.Unlock()
+
+	for _, rec := range evicted {
+		_ = h.next.Handle(ctx, rec)
+	}
+
+	if suppressed > 0 {
+		r.AddAttrs(slog.Int("repeated", suppressed))
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// evictLocked drops least-recently-touched entries once the tracked
+// key count exceeds max, emitting each dropped entry's pending
+// suppressed count as a trailing record (built from its last-seen
+// occurrence) rather than silently discarding it - a key evicted mid
+// burst still gets its "repeated=N" summary, it just arrives a little
+// earlier than it would have if the key had stayed tracked.
+func (h *dedupingHandler) evictLocked() []slog.Record {
+	var evicted []slog.Record
+	for len(h.state.entries) > h.max {
+		back := h.state.order.Back()
+		if back == nil {
+			return evicted
+		}
+
+		key := back.Value.(uint64)
+		entry := h.state.entries[key]
+		h.state.order.Remove(back)
+		delete(h.state.entries, key)
+
+		if entry.suppressed > 0 {
+			rec := entry.record.Clone()
+			rec.AddAttrs(slog.Int("repeated", entry.suppressed))
+			evicted = append(evicted, rec)
+		}
+	}
+	return evicted
+}
+
+// hashRecord builds the dedup key from a record's level, message and
+// attrs - both the ones attached at the call site (r.Attrs) and the
+// ones bound earlier via WithAttrs/WithGroup (bound), since both are
+// part of what makes two log lines "the same" for dedup purposes.
+func hashRecord(r slog.Record, bound []slog.Attr) uint64 {
+	attrs := make([]string, 0, r.NumAttrs()+len(bound))
+	for _, a := range bound {
+		attrs = append(attrs, a.Key+"="+a.Value.String())
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a.Key+"="+a.Value.String())
+		return true
+	})
+	sort.Strings(attrs)
+
+	hasher := fnv.New64a()
+	_, _ = hasher.Write([]byte(r.Level.String()))
+	_, _ = hasher.Write([]byte{0})
+	_, _ = hasher.Write([]byte(r.Message))
+	for _, a := range attrs {
+		_, _ = hasher.Write([]byte{0})
+		_, _ = hasher.Write([]byte(a))
+	}
+	return hasher.Sum64()
+}
@@ -0,0 +1,134 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thanos-io/thanos/pkg/testutil"
+)
+
+func TestDedupingHandler_SuppressesRepeats(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewDedupingHandler(slog.NewTextHandler(&buf, nil), time.Minute, 16)
+	l := slog.New(h)
+
+	for i := 0; i < 5; i++ {
+		l.Error("scrape failed", "target", "a")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	testutil.Equals(t, 1, len(lines))
+}
+
+func TestDedupingHandler_DistinctKeyEmitsImmediately(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewDedupingHandler(slog.NewTextHandler(&buf, nil), time.Minute, 16)
+	l := slog.New(h)
+
+	l.Error("scrape failed", "target", "a")
+	l.Error("scrape failed", "target", "a")
+	l.Error("scrape failed", "target", "a")
+	l.Error("scrape failed", "target", "b") // distinct key, emits immediately.
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	testutil.Equals(t, 2, len(lines))
+	testutil.Assert(t, strings.Contains(lines[1], `target=b`))
+}
+
+func TestDedupingHandler_EvictsLeastRecentlyTouched(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewDedupingHandler(slog.NewTextHandler(&buf, nil), time.Minute, 1)
+	l := slog.New(h)
+
+	l.Error("a")
+	l.Error("b") // evicts "a"'s entry since max tracked keys is 1.
+	l.Error("a") // treated as new again, not suppressed.
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	testutil.Equals(t, 3, len(lines))
+}
+
+func TestDedupingHandler_EvictionEmitsPendingCount(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewDedupingHandler(slog.NewTextHandler(&buf, nil), time.Minute, 1)
+	l := slog.New(h)
+
+	l.Error("a")
+	l.Error("a") // suppressed, bumps a's pending count to 1.
+	l.Error("b") // evicts "a" before tracking "b"; its pending count must not be dropped.
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	testutil.Equals(t, 3, len(lines))
+	testutil.Assert(t, strings.Contains(lines[0], "msg=a"))
+	testutil.Assert(t, strings.Contains(lines[1], "msg=a"))
+	testutil.Assert(t, strings.Contains(lines[1], "repeated=1"))
+	testutil.Assert(t, strings.Contains(lines[2], "msg=b"))
+}
+
+func TestDedupingHandler_WithAttrsSharesEvictionState(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewDedupingHandler(slog.NewTextHandler(&buf, nil), time.Minute, 1)
+	root := slog.New(h)
+	derived := root.With("component", "scraper")
+
+	derived.Error("x")
+	root.Error("x") // bound attrs differ, so this is a distinct key from derived's entry - and evicts it, since max tracked keys is shared at 1.
+	derived.Error("x") // derived's entry was evicted, so this is treated as new again rather than suppressed.
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	testutil.Equals(t, 3, len(lines))
+}
+
+func TestDedupingHandler_BoundAttrsDoNotCollide(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewDedupingHandler(slog.NewTextHandler(&buf, nil), time.Minute, 16)
+	root := slog.New(h)
+
+	// Two different requests logging the same message through loggers
+	// bound to different request_id attrs must not dedup into one
+	// line - that would silently drop one request's correlating
+	// request_id.
+	root.With("request_id", "req-1").Error("scrape failed", "target", "a")
+	root.With("request_id", "req-2").Error("scrape failed", "target", "a")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	testutil.Equals(t, 2, len(lines))
+	testutil.Assert(t, strings.Contains(lines[0], "request_id=req-1"))
+	testutil.Assert(t, strings.Contains(lines[1], "request_id=req-2"))
+}
+
+func TestDedupingHandler_WindowExpiry(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewDedupingHandler(slog.NewTextHandler(&buf, nil), time.Nanosecond, 16)
+	l := slog.New(h)
+
+	l.Error("scrape failed")
+	time.Sleep(time.Millisecond)
+	l.Error("scrape failed")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	testutil.Equals(t, 2, len(lines))
+}
+
+func TestGoKitAdapter_Log(t *testing.T) {
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, nil))
+	adapter := NewGoKitAdapter(l)
+
+	testutil.Ok(t, adapter.Log("level", "warn", "msg", "hello", "attempt", 3))
+
+	out := buf.String()
+	testutil.Assert(t, strings.Contains(out, "level=WARN"))
+	testutil.Assert(t, strings.Contains(out, "msg=hello"))
+	testutil.Assert(t, strings.Contains(out, "attempt=3"))
+}
+
+func TestWithRequestID_RoundTrip(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "abc-123")
+	testutil.Equals(t, "abc-123", RequestIDFromContext(ctx))
+	testutil.Equals(t, "", RequestIDFromContext(context.Background()))
+}
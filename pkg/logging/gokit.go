@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	gokitlog "github.com/go-kit/kit/log"
+)
+
+// gokitAdapter lets the growing number of packages still written
+// against go-kit/log.Logger (runutil, promclient and friends) log
+// through the same slog handler chain — including deduping — as the
+// rest of the binary, instead of forking the log stream in two.
+type gokitAdapter struct {
+	l *slog.Logger
+}
+
+// NewGoKitAdapter wraps l as a go-kit/log.Logger. keyvals passed to Log
+// are interpreted with go-kit's own conventions: a "level" pair selects
+// the slog level (default info), a "msg" pair becomes the record
+// message, and everything else becomes attrs.
+func NewGoKitAdapter(l *slog.Logger) gokitlog.Logger {
+	return &gokitAdapter{l: l}
+}
+
+func (a *gokitAdapter) Log(keyvals ...interface{}) error {
+	level := slog.LevelInfo
+	msg := ""
+	attrs := make([]any, 0, len(keyvals))
+
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		key := fmt.Sprint(keyvals[i])
+		val := keyvals[i+1]
+
+		switch key {
+		case "level":
+			level = gokitLevelToSlog(val)
+		case "msg":
+			msg = fmt.Sprint(val)
+		default:
+			attrs = append(attrs, key, val)
+		}
+	}
+
+	a.l.Log(context.Background(), level, msg, attrs...)
+	return nil
+}
+
+func gokitLevelToSlog(v interface{}) slog.Level {
+	switch fmt.Sprint(v) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,77 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// requestIDHeader is the HTTP header HTTPMiddleware reads an inbound
+// request ID from and sets the (read or generated) ID on in its
+// response. Outbound requests are not given this header.
+const requestIDHeader = "X-Request-ID"
+
+// requestIDMetadataKey is the gRPC metadata equivalent of
+// requestIDHeader for StoreAPI calls between Thanos components.
+const requestIDMetadataKey = "x-request-id"
+
+type requestIDCtxKey struct{}
+
+// WithRequestID returns a context carrying id, retrievable with
+// RequestIDFromContext.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx, or "" if
+// none was set.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDCtxKey{}).(string)
+	return id
+}
+
+// LoggerWithRequestID returns l with a request_id attribute bound, so
+// every subsequent log call on the chain carries a consistent
+// correlation ID across a StoreAPI call.
+func LoggerWithRequestID(l *slog.Logger, ctx context.Context) *slog.Logger {
+	id := RequestIDFromContext(ctx)
+	if id == "" {
+		return l
+	}
+	return l.With("request_id", id)
+}
+
+// HTTPMiddleware extracts the request ID from requestIDHeader, or
+// generates a new one if absent, and injects it into the request
+// context so downstream StoreAPI calls log a consistent request_id.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if id == "" {
+			id = uuid.NewString()
+		}
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(WithRequestID(r.Context(), id)))
+	})
+}
+
+// UnaryServerInterceptor is the gRPC equivalent of HTTPMiddleware for
+// StoreAPI and other internal gRPC servers.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		id := ""
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if vals := md.Get(requestIDMetadataKey); len(vals) > 0 {
+				id = vals[0]
+			}
+		}
+		if id == "" {
+			id = uuid.NewString()
+		}
+		return handler(WithRequestID(ctx, id), req)
+	}
+}
@@ -0,0 +1,104 @@
+package receive
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/storage"
+)
+
+// maxWriteErrors caps how many per-series failures are included in a
+// rejected remote-write response body. A tenant pushing a batch with
+// thousands of out-of-order series shouldn't make the receiver build
+// and serialize a multi-megabyte error response; the header below
+// tells the client how many more were dropped from the body.
+const maxWriteErrors = 64
+
+// writeErrorsTruncatedHeader reports how many per-series failures were
+// omitted from the JSON body because maxWriteErrors was hit.
+const writeErrorsTruncatedHeader = "X-Prometheus-Remote-Write-Errors-Truncated"
+
+// seriesWriteError is one entry of the per-series failure list returned
+// in a rejected remote-write response body.
+type seriesWriteError struct {
+	Labels    string `json:"labels"`
+	Timestamp int64  `json:"timestamp"`
+	Reason    string `json:"reason"`
+}
+
+// appendResult accumulates per-series failures across a batch so the
+// handler can keep appending the rest of a request instead of aborting
+// on the first bad series, and then classify the overall response
+// status once the whole batch has been tried.
+type appendResult struct {
+	failures  []seriesWriteError
+	truncated int
+	status    int // highest-severity status observed across all failures; 0 if none.
+}
+
+func (a *appendResult) recordFailure(labels []prompb.Label, timestamp int64, err error) {
+	status := classifyAppendErr(err)
+	if status > a.status {
+		a.status = status
+	}
+
+	if len(a.failures) >= maxWriteErrors {
+		a.truncated++
+		return
+	}
+	a.failures = append(a.failures, seriesWriteError{
+		Labels:    formatLabels(labels),
+		Timestamp: timestamp,
+		Reason:    err.Error(),
+	})
+}
+
+func (a *appendResult) ok() bool {
+	return len(a.failures) == 0 && a.truncated == 0
+}
+
+// writeHTTP replies with the aggregated per-series failures. Status is
+// 400 unless at least one failure was a genuine server-side error, in
+// which case 500 takes priority: a client can't fix a server failure by
+// adjusting its data, and collapsing it into a 400 would make it drop
+// data that a retry could still deliver.
+func (a *appendResult) writeHTTP(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	if a.truncated > 0 {
+		w.Header().Set(writeErrorsTruncatedHeader, fmt.Sprintf("%d", a.truncated))
+	}
+	w.WriteHeader(a.status)
+	_ = json.NewEncoder(w).Encode(struct {
+		Errors []seriesWriteError `json:"errors"`
+	}{Errors: a.failures})
+}
+
+// classifyAppendErr maps a TSDB appender error to the HTTP status a
+// remote-write client should see. Prometheus' own convention is that
+// data the client sent is a 400 (so its remote-write queue doesn't
+// retry-storm something that will never succeed), while anything else
+// is a genuine server failure and stays a 5xx.
+func classifyAppendErr(err error) int {
+	switch {
+	case errors.Is(err, storage.ErrOutOfOrderSample),
+		errors.Is(err, storage.ErrOutOfBounds),
+		errors.Is(err, storage.ErrDuplicateSampleForTimestamp):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func formatLabels(labels []prompb.Label) string {
+	pairs := make([]string, 0, len(labels))
+	for _, l := range labels {
+		pairs = append(pairs, fmt.Sprintf(`%s=%q`, l.Name, l.Value))
+	}
+	sort.Strings(pairs)
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
@@ -0,0 +1,62 @@
+package receive
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/prometheus/prometheus/storage"
+
+	"github.com/thanos-io/thanos/pkg/testutil"
+)
+
+func TestClassifyAppendErr(t *testing.T) {
+	testutil.Equals(t, http.StatusBadRequest, classifyAppendErr(storage.ErrOutOfOrderSample))
+	testutil.Equals(t, http.StatusBadRequest, classifyAppendErr(storage.ErrOutOfBounds))
+	testutil.Equals(t, http.StatusBadRequest, classifyAppendErr(storage.ErrDuplicateSampleForTimestamp))
+	testutil.Equals(t, http.StatusBadRequest, classifyAppendErr(errors.Wrap(storage.ErrOutOfOrderSample, "appending")))
+	testutil.Equals(t, http.StatusInternalServerError, classifyAppendErr(errors.New("disk full")))
+}
+
+func TestAppendResult_AggregatesAndCaps(t *testing.T) {
+	var result appendResult
+	labels := []prompb.Label{{Name: "__name__", Value: "up"}}
+
+	for i := 0; i < maxWriteErrors+5; i++ {
+		result.recordFailure(labels, int64(i), storage.ErrOutOfOrderSample)
+	}
+
+	testutil.Assert(t, !result.ok())
+	testutil.Equals(t, maxWriteErrors, len(result.failures))
+	testutil.Equals(t, 5, result.truncated)
+	testutil.Equals(t, http.StatusBadRequest, result.status)
+}
+
+func TestAppendResult_ServerErrorTakesPriority(t *testing.T) {
+	var result appendResult
+	labels := []prompb.Label{{Name: "__name__", Value: "up"}}
+
+	result.recordFailure(labels, 0, storage.ErrOutOfOrderSample)
+	result.recordFailure(labels, 1, errors.New("disk full"))
+
+	testutil.Equals(t, http.StatusInternalServerError, result.status)
+}
+
+func TestAppendResult_WriteHTTP(t *testing.T) {
+	var result appendResult
+	labels := []prompb.Label{{Name: "__name__", Value: "up"}, {Name: "job", Value: "node"}}
+	result.recordFailure(labels, 42, storage.ErrOutOfOrderSample)
+
+	rec := httptest.NewRecorder()
+	result.writeHTTP(rec)
+
+	testutil.Equals(t, http.StatusBadRequest, rec.Code)
+	testutil.Assert(t, len(rec.Body.String()) > 0)
+}
+
+func TestFormatLabels(t *testing.T) {
+	labels := []prompb.Label{{Name: "job", Value: "node"}, {Name: "__name__", Value: "up"}}
+	testutil.Equals(t, `{__name__="up", job="node"}`, formatLabels(labels))
+}
@@ -0,0 +1,130 @@
+package receive
+
+import (
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/thanos-io/thanos/pkg/featureflag"
+	"github.com/thanos-io/thanos/pkg/logging"
+)
+
+// Pipeline composes the receiver's write path as discrete, pluggable
+// stages - Decoder, TenantResolver, Sharder, Replicator and Appender -
+// chained behind the content-negotiation, per-series error aggregation
+// and response encoding every deployment needs regardless of which
+// stage implementations it uses. A third party swaps in, say, a
+// JWT-based TenantResolver or a non-TSDB Appender by building a
+// Pipeline literal instead of forking this package.
+type Pipeline struct {
+	Decoder        Decoder
+	TenantResolver TenantResolver
+	Sharder        Sharder
+	Replicator     Replicator
+	Appender       Appender
+	Features       *featureflag.Set
+
+	logger *slog.Logger
+}
+
+// NewPipeline builds the production Pipeline: content-negotiated v1/v2
+// decoding, the fixed tenant header, hashring-based sharding and local
+// replication. Callers needing custom stages build a Pipeline literal
+// directly and override only the stage they care about.
+func NewPipeline(logger *slog.Logger, hashring Hashring, appender Appender, features *featureflag.Set) *Pipeline {
+	return &Pipeline{
+		Decoder:        NegotiatingDecoder{},
+		TenantResolver: NewHeaderTenantResolver(),
+		Sharder:        HashringSharder{Hashring: hashring},
+		Replicator:     LocalReplicator{},
+		Appender:       appender,
+		Features:       features,
+		logger:         logger,
+	}
+}
+
+// Handler wraps the pipeline's ServeHTTP with request-ID correlation:
+// the request ID is put in the request context, the pipeline's own log
+// lines carry it as a request_id attribute, and a custom stage can read
+// it with logging.RequestIDFromContext.
+func (p *Pipeline) Handler() http.Handler {
+	return logging.HTTPMiddleware(http.HandlerFunc(p.ServeHTTP))
+}
+
+// ServeHTTP decodes, resolves, shards, replicates and appends an
+// incoming remote-write request, then replies with the
+// version-appropriate response: either a 204 (with the accepted-count
+// headers for v2), or, if any stage rejected part of the batch, a 400
+// with structured per-series failures (500 when any failure was a
+// server-side error).
+func (p *Pipeline) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	base := p.logger
+	if base == nil {
+		// A Pipeline literal built outside this package cannot set logger.
+		base = slog.Default()
+	}
+	logger := logging.LoggerWithRequestID(base, r.Context())
+
+	version, series, err := p.Decoder.Decode(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if version == remoteWriteVersion2 && !p.Features.Enabled(featureflag.RemoteWrite20) {
+		http.Error(w, "remote write 2.0 is experimental and must be enabled with --enable-feature="+featureflag.RemoteWrite20, http.StatusUnsupportedMediaType)
+		return
+	}
+
+	tenant, err := p.TenantResolver.ResolveTenant(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var (
+		result                         appendResult
+		samples, histograms, exemplars int
+	)
+	for _, s := range series {
+		if err := p.replicate(r, tenant, s); err != nil {
+			logger.Error("failed to write series", "err", err, "labels", formatLabels(s.Labels))
+			result.recordFailure(s.Labels, firstTimestamp(s), err)
+			continue
+		}
+		samples += len(s.Samples)
+		histograms += len(s.Histograms)
+		exemplars += len(s.Exemplars)
+	}
+
+	if !result.ok() {
+		result.writeHTTP(w)
+		return
+	}
+
+	if version == remoteWriteVersion2 {
+		w.Header().Set(remoteWriteVersionHeader, string(remoteWriteVersion2))
+		w.Header().Set("X-Prometheus-Remote-Write-Samples-Written", strconv.Itoa(samples))
+		w.Header().Set("X-Prometheus-Remote-Write-Histograms-Written", strconv.Itoa(histograms))
+		w.Header().Set("X-Prometheus-Remote-Write-Exemplars-Written", strconv.Itoa(exemplars))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *Pipeline) replicate(r *http.Request, tenant string, s Series) error {
+	node, err := p.Sharder.Shard(tenant, s)
+	if err != nil {
+		return err
+	}
+	return p.Replicator.Replicate(r.Context(), node, tenant, s, p.Appender)
+}
+
+func firstTimestamp(s Series) int64 {
+	if len(s.Samples) > 0 {
+		return s.Samples[0].Timestamp
+	}
+	if len(s.Histograms) > 0 {
+		return s.Histograms[0].Timestamp
+	}
+	return 0
+}
@@ -0,0 +1,31 @@
+package receive
+
+import "context"
+
+// Appender is the pipeline's final stage: it writes a tenant's series
+// to storage, usually a per-tenant TSDB head block.
+type Appender interface {
+	Append(tenant string, s Series) error
+}
+
+// Replicator writes a series on behalf of the single node a Sharder
+// selected for it, appending through appender. Declared as an interface
+// so deployments that need an alternative storage backend, or a
+// replication strategy such as quorum fan-out, can supply their own
+// without forking Pipeline.
+type Replicator interface {
+	Replicate(ctx context.Context, node, tenant string, s Series, appender Appender) error
+}
+
+// LocalReplicator appends directly through appender, ignoring node, so
+// every series is written locally whichever node it was sharded to. It
+// is correct for single-node deployments and tests. No Replicator in
+// this package forwards series to other nodes.
+type LocalReplicator struct{}
+
+// Replicate implements Replicator.
+func (LocalReplicator) Replicate(_ context.Context, _, tenant string, s Series, appender Appender) error {
+	return appender.Append(tenant, s)
+}
+
+var _ Replicator = LocalReplicator{}
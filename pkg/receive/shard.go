@@ -0,0 +1,31 @@
+package receive
+
+// Hashring is the subset of the hashring package's lookup behavior a
+// HashringSharder needs. It is declared locally so this package's
+// pipeline abstraction doesn't take a hard dependency on the full
+// hashring API.
+type Hashring interface {
+	GetN(tenant string, s Series, replicaIndex int) (string, error)
+}
+
+// Sharder maps a tenant's series to the receive node that should own
+// it, so a Replicator knows which peer to fan the write out to.
+// Declared as an interface so a Pipeline can be built and unit tested
+// without a real hashring.
+type Sharder interface {
+	Shard(tenant string, s Series) (node string, err error)
+}
+
+// HashringSharder is the Sharder Thanos receive uses in production: it
+// looks up the primary node for a series in the tenant's configured
+// hashring.
+type HashringSharder struct {
+	Hashring Hashring
+}
+
+// Shard implements Sharder.
+func (s HashringSharder) Shard(tenant string, series Series) (string, error) {
+	return s.Hashring.GetN(tenant, series, 0)
+}
+
+var _ Sharder = HashringSharder{}
@@ -0,0 +1,44 @@
+package receive
+
+import "net/http"
+
+// defaultTenantHeader is the header Thanos receive reads a tenant ID
+// from when no custom TenantResolver is configured.
+const defaultTenantHeader = "THANOS-TENANT"
+
+// defaultTenant is used when defaultTenantHeader is absent, preserving
+// the single-tenant behavior every existing receive deployment already
+// relies on.
+const defaultTenant = "default-tenant"
+
+// TenantResolver determines which tenant a remote-write request
+// belongs to, so a Sharder can route its series within the right
+// hashring and an Appender can write it to the right tenant's TSDB.
+// Third parties needing JWT- or client-cert-based tenancy implement
+// this interface and plug it into a Pipeline in place of
+// HeaderTenantResolver.
+type TenantResolver interface {
+	ResolveTenant(r *http.Request) (string, error)
+}
+
+// HeaderTenantResolver resolves the tenant from a fixed HTTP header,
+// falling back to defaultTenant when it's absent.
+type HeaderTenantResolver struct {
+	Header string
+}
+
+// NewHeaderTenantResolver returns the HeaderTenantResolver Thanos
+// receive uses by default, reading defaultTenantHeader.
+func NewHeaderTenantResolver() HeaderTenantResolver {
+	return HeaderTenantResolver{Header: defaultTenantHeader}
+}
+
+// ResolveTenant implements TenantResolver.
+func (h HeaderTenantResolver) ResolveTenant(r *http.Request) (string, error) {
+	if v := r.Header.Get(h.Header); v != "" {
+		return v, nil
+	}
+	return defaultTenant, nil
+}
+
+var _ TenantResolver = HeaderTenantResolver{}
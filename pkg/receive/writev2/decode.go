@@ -0,0 +1,218 @@
+// Package writev2 decodes the Prometheus Remote Write 2.0 wire format
+// (io.prometheus.write.v2.Request) into the label/sample tuples the
+// receiver's appender understands.
+//
+// The v2 message interns every label name and value once in a flat
+// `symbols []string` table and refers to them from each series via
+// `LabelsRefs []uint32`, an even-length slice of (name, value) index
+// pairs. This halves payload size for high-cardinality, low-churn label
+// sets at the cost of requiring validation: a malicious or buggy client
+// can submit out-of-range or odd-length refs to try to crash or OOM the
+// receiver, so every reference is range-checked before use.
+package writev2
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+)
+
+// ErrInvalidSymbolRef is returned when a series references the symbol
+// table out of bounds or with an odd number of label refs.
+var ErrInvalidSymbolRef = errors.New("remote write 2.0: invalid symbol table reference")
+
+// Series is a single decoded v2 time series with its symbol references
+// already resolved to label strings.
+type Series struct {
+	Labels     []prompb.Label
+	Samples    []prompb.Sample
+	Histograms []prompb.Histogram
+	Exemplars  []prompb.Exemplar
+
+	// Metadata is carried inline per-series in v2, unlike v1 where it
+	// travels in a separate MetricMetadata message.
+	Metadata Metadata
+}
+
+// Metadata mirrors writev2.Metadata but with the type already resolved
+// to its string form for convenience of callers that don't want to
+// depend on the raw protobuf enum.
+type Metadata struct {
+	Type string
+	Help string
+	Unit string
+}
+
+// Decode resolves every series in req against its symbol table and
+// returns the fully materialized series. It rejects any series whose
+// LabelsRefs are malformed before doing any allocation-heavy work, so a
+// request crafted to reference far out-of-bounds indexes fails fast
+// instead of attempting to decode first.
+func Decode(req *writev2.Request) ([]Series, error) {
+	symbols := req.GetSymbols()
+
+	out := make([]Series, 0, len(req.GetTimeseries()))
+	for _, ts := range req.GetTimeseries() {
+		labels, err := resolveLabels(symbols, ts.GetLabelsRefs())
+		if err != nil {
+			return nil, err
+		}
+
+		exemplars, err := resolveExemplars(symbols, ts.GetExemplars())
+		if err != nil {
+			return nil, err
+		}
+
+		md := ts.GetMetadata()
+		helpUnit, err := resolveHelpUnit(symbols, md)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, Series{
+			Labels:     labels,
+			Samples:    convertSamples(ts.GetSamples()),
+			Histograms: convertHistograms(ts.GetHistograms()),
+			Exemplars:  exemplars,
+			Metadata: Metadata{
+				Type: md.GetType().String(),
+				Help: helpUnit.help,
+				Unit: helpUnit.unit,
+			},
+		})
+	}
+	return out, nil
+}
+
+// resolveLabels turns a flat (name, value) index pair slice into label
+// strings, validating every index against the symbol table bounds.
+func resolveLabels(symbols []string, refs []uint32) ([]prompb.Label, error) {
+	if len(refs)%2 != 0 {
+		return nil, errors.Wrap(ErrInvalidSymbolRef, "odd number of label refs")
+	}
+
+	labels := make([]prompb.Label, 0, len(refs)/2)
+	for i := 0; i < len(refs); i += 2 {
+		nameRef, valueRef := refs[i], refs[i+1]
+
+		name, err := lookupSymbol(symbols, nameRef)
+		if err != nil {
+			return nil, err
+		}
+		value, err := lookupSymbol(symbols, valueRef)
+		if err != nil {
+			return nil, err
+		}
+
+		labels = append(labels, prompb.Label{Name: name, Value: value})
+	}
+	return labels, nil
+}
+
+// resolveExemplars resolves each v2 exemplar's symbol-table label refs
+// the same way resolveLabels does for series labels - an exemplar's
+// labels are just as much a LabelsRefs pair slice, and skipping that
+// resolution would silently drop them instead of rejecting a malformed
+// reference.
+func resolveExemplars(symbols []string, in []writev2.Exemplar) ([]prompb.Exemplar, error) {
+	out := make([]prompb.Exemplar, 0, len(in))
+	for _, e := range in {
+		labels, err := resolveLabels(symbols, e.GetLabelsRefs())
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, prompb.Exemplar{
+			Labels:    labels,
+			Value:     e.GetValue(),
+			Timestamp: e.GetTimestamp(),
+		})
+	}
+	return out, nil
+}
+
+func convertSamples(in []writev2.Sample) []prompb.Sample {
+	out := make([]prompb.Sample, len(in))
+	for i, s := range in {
+		out[i] = prompb.Sample{Value: s.GetValue(), Timestamp: s.GetTimestamp()}
+	}
+	return out
+}
+
+// convertHistograms maps the v2 wire representation of a native
+// histogram onto prompb.Histogram, which the rest of the append path
+// already understands from v1 native-histogram ingestion. Both share
+// the same sparse-bucket-spans encoding, so this is a field-for-field
+// copy rather than any reinterpretation of the data - except that the
+// counts themselves come in either an integer-delta or a float-count
+// variant, and copying the wrong one silently zeroes out a float
+// histogram's buckets.
+func convertHistograms(in []writev2.Histogram) []prompb.Histogram {
+	out := make([]prompb.Histogram, len(in))
+	for i, h := range in {
+		out[i] = convertHistogram(h)
+	}
+	return out
+}
+
+func convertHistogram(h writev2.Histogram) prompb.Histogram {
+	out := prompb.Histogram{
+		Sum:           h.GetSum(),
+		Schema:        h.GetSchema(),
+		ZeroThreshold: h.GetZeroThreshold(),
+		NegativeSpans: convertSpans(h.GetNegativeSpans()),
+		PositiveSpans: convertSpans(h.GetPositiveSpans()),
+		ResetHint:     prompb.Histogram_ResetHint(h.GetResetHint()),
+		Timestamp:     h.GetTimestamp(),
+	}
+
+	if h.IsFloatHistogram() {
+		out.Count = &prompb.Histogram_CountFloat{CountFloat: h.GetCountFloat()}
+		out.ZeroCount = &prompb.Histogram_ZeroCountFloat{ZeroCountFloat: h.GetZeroCountFloat()}
+		out.NegativeCounts = h.GetNegativeCounts()
+		out.PositiveCounts = h.GetPositiveCounts()
+		return out
+	}
+
+	out.Count = &prompb.Histogram_CountInt{CountInt: h.GetCountInt()}
+	out.ZeroCount = &prompb.Histogram_ZeroCountInt{ZeroCountInt: h.GetZeroCountInt()}
+	out.NegativeDeltas = h.GetNegativeDeltas()
+	out.PositiveDeltas = h.GetPositiveDeltas()
+	return out
+}
+
+func convertSpans(in []writev2.BucketSpan) []prompb.BucketSpan {
+	out := make([]prompb.BucketSpan, len(in))
+	for i, s := range in {
+		out[i] = prompb.BucketSpan{Offset: s.GetOffset(), Length: s.GetLength()}
+	}
+	return out
+}
+
+func lookupSymbol(symbols []string, ref uint32) (string, error) {
+	if int(ref) >= len(symbols) {
+		return "", errors.Wrapf(ErrInvalidSymbolRef, "ref %d out of range for symbol table of size %d", ref, len(symbols))
+	}
+	return symbols[ref], nil
+}
+
+type helpUnit struct {
+	help string
+	unit string
+}
+
+// resolveHelpUnit resolves a series' inline metadata help/unit refs. md
+// is the zero value when the series carried no metadata; HelpRef and
+// UnitRef are then both 0, which by symbol-table convention resolves
+// to the empty string at index 0, so no separate presence check is
+// needed.
+func resolveHelpUnit(symbols []string, md writev2.Metadata) (helpUnit, error) {
+	help, err := lookupSymbol(symbols, md.GetHelpRef())
+	if err != nil {
+		return helpUnit{}, err
+	}
+	unit, err := lookupSymbol(symbols, md.GetUnitRef())
+	if err != nil {
+		return helpUnit{}, err
+	}
+	return helpUnit{help: help, unit: unit}, nil
+}
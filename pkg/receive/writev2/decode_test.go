@@ -0,0 +1,109 @@
+package writev2
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/prompb"
+	writev2 "github.com/prometheus/prometheus/prompb/io/prometheus/write/v2"
+
+	"github.com/thanos-io/thanos/pkg/testutil"
+)
+
+func TestDecode(t *testing.T) {
+	req := &writev2.Request{
+		Symbols: []string{"", "__name__", "up", "job", "prometheus"},
+		Timeseries: []writev2.TimeSeries{
+			{
+				LabelsRefs: []uint32{1, 2, 3, 4},
+				Samples:    []writev2.Sample{{Value: 1, Timestamp: 1000}},
+			},
+		},
+	}
+
+	series, err := Decode(req)
+	testutil.Ok(t, err)
+	testutil.Equals(t, 1, len(series))
+	testutil.Equals(t, []prompb.Label{
+		{Name: "__name__", Value: "up"},
+		{Name: "job", Value: "prometheus"},
+	}, series[0].Labels)
+}
+
+func TestDecode_ResolvesExemplarLabels(t *testing.T) {
+	req := &writev2.Request{
+		Symbols: []string{"", "__name__", "up", "trace_id", "abc123"},
+		Timeseries: []writev2.TimeSeries{
+			{
+				LabelsRefs: []uint32{1, 2},
+				Exemplars: []writev2.Exemplar{
+					{LabelsRefs: []uint32{3, 4}, Value: 1, Timestamp: 1000},
+				},
+			},
+		},
+	}
+
+	series, err := Decode(req)
+	testutil.Ok(t, err)
+	testutil.Equals(t, 1, len(series[0].Exemplars))
+	testutil.Equals(t, []prompb.Label{{Name: "trace_id", Value: "abc123"}}, series[0].Exemplars[0].Labels)
+}
+
+func TestDecode_ConvertsFloatHistograms(t *testing.T) {
+	req := &writev2.Request{
+		Symbols: []string{"", "__name__", "up"},
+		Timeseries: []writev2.TimeSeries{
+			{
+				LabelsRefs: []uint32{1, 2},
+				Histograms: []writev2.Histogram{
+					{
+						Count:          &writev2.Histogram_CountFloat{CountFloat: 12},
+						ZeroCount:      &writev2.Histogram_ZeroCountFloat{ZeroCountFloat: 1},
+						Sum:            18.4,
+						Schema:         1,
+						PositiveSpans:  []writev2.BucketSpan{{Offset: 0, Length: 2}},
+						PositiveCounts: []float64{3, 4},
+						NegativeSpans:  []writev2.BucketSpan{{Offset: 0, Length: 1}},
+						NegativeCounts: []float64{5},
+						Timestamp:      1000,
+					},
+				},
+			},
+		},
+	}
+
+	series, err := Decode(req)
+	testutil.Ok(t, err)
+	testutil.Equals(t, 1, len(series[0].Histograms))
+
+	h := series[0].Histograms[0]
+	testutil.Equals(t, &prompb.Histogram_CountFloat{CountFloat: 12}, h.Count)
+	testutil.Equals(t, &prompb.Histogram_ZeroCountFloat{ZeroCountFloat: 1}, h.ZeroCount)
+	testutil.Equals(t, []float64{3, 4}, h.PositiveCounts)
+	testutil.Equals(t, []float64{5}, h.NegativeCounts)
+	testutil.Equals(t, []int64(nil), h.PositiveDeltas)
+	testutil.Equals(t, []int64(nil), h.NegativeDeltas)
+}
+
+func TestDecode_OddLabelRefs(t *testing.T) {
+	req := &writev2.Request{
+		Symbols: []string{"", "__name__", "up"},
+		Timeseries: []writev2.TimeSeries{
+			{LabelsRefs: []uint32{1, 2, 1}},
+		},
+	}
+
+	_, err := Decode(req)
+	testutil.NotOk(t, err)
+}
+
+func TestDecode_OutOfRangeRef(t *testing.T) {
+	req := &writev2.Request{
+		Symbols: []string{"", "__name__"},
+		Timeseries: []writev2.TimeSeries{
+			{LabelsRefs: []uint32{1, 42}},
+		},
+	}
+
+	_, err := Decode(req)
+	testutil.NotOk(t, err)
+}
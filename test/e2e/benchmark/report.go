@@ -0,0 +1,174 @@
+package benchmark
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/thanos-io/thanos/pkg/promclient"
+)
+
+// Report is the result of one benchmark run, in the shape written to
+// the JSON report file. It is not exported as Prometheus metrics.
+type Report struct {
+	Scenario Scenario `json:"scenario"`
+
+	// Throughput is the average accepted samples per second across all
+	// receivers over the run.
+	ThroughputSamplesPerSecond float64 `json:"throughput_samples_per_second"`
+
+	// AppendLatencySecondsP50/P99 are append-call latency percentiles,
+	// read from the first receiver only; the other receivers' latencies
+	// are not included.
+	AppendLatencySecondsP50 float64 `json:"append_latency_seconds_p50"`
+	AppendLatencySecondsP99 float64 `json:"append_latency_seconds_p99"`
+
+	// PerReceiver holds the resource-usage snapshot for each receiver
+	// instance at the end of the run.
+	PerReceiver []ReceiverUsage `json:"per_receiver"`
+
+	// NetworkBytesTotal is the cumulative bytes written to all
+	// receivers over the run, as reported by their HTTP server metrics.
+	NetworkBytesTotal float64 `json:"network_bytes_total"`
+}
+
+// ReceiverUsage is a single receiver's resource snapshot at the end of
+// a benchmark run.
+type ReceiverUsage struct {
+	Addr             string  `json:"addr"`
+	ResidentMemBytes float64 `json:"resident_mem_bytes"`
+	HeapAllocBytes   float64 `json:"heap_alloc_bytes"`
+	Goroutines       float64 `json:"goroutines"`
+}
+
+// Collect scrapes each receiver's /metrics endpoint and assembles a
+// Report for the given scenario. start/end bound the run so throughput
+// can be computed from the counters' delta over the interval.
+func Collect(ctx context.Context, scenario Scenario, receiverHTTPAddrs []string, start, end time.Time) (Report, error) {
+	report := Report{Scenario: scenario, PerReceiver: make([]ReceiverUsage, 0, len(receiverHTTPAddrs))}
+
+	elapsed := end.Sub(start).Seconds()
+	if elapsed <= 0 {
+		return Report{}, errors.New("benchmark: end must be after start")
+	}
+
+	var totalSamples, totalBytes float64
+	for _, addr := range receiverHTTPAddrs {
+		samples, err := scalarMetric(ctx, addr, "thanos_receive_samples_total")
+		if err != nil {
+			return Report{}, err
+		}
+		totalSamples += samples
+
+		bytes, err := scalarMetric(ctx, addr, "thanos_receive_http_request_size_bytes_sum")
+		if err != nil {
+			return Report{}, err
+		}
+		totalBytes += bytes
+
+		rss, err := scalarMetric(ctx, addr, "process_resident_memory_bytes")
+		if err != nil {
+			return Report{}, err
+		}
+		heap, err := scalarMetric(ctx, addr, "go_memstats_heap_alloc_bytes")
+		if err != nil {
+			return Report{}, err
+		}
+		goroutines, err := scalarMetric(ctx, addr, "go_goroutines")
+		if err != nil {
+			return Report{}, err
+		}
+
+		report.PerReceiver = append(report.PerReceiver, ReceiverUsage{
+			Addr:             addr,
+			ResidentMemBytes: rss,
+			HeapAllocBytes:   heap,
+			Goroutines:       goroutines,
+		})
+	}
+
+	report.ThroughputSamplesPerSecond = totalSamples / elapsed
+	report.NetworkBytesTotal = totalBytes
+
+	p50, err := scalarMetric(ctx, receiverHTTPAddrs[0], "thanos_receive_write_duration_seconds{quantile=\"0.5\"}")
+	if err == nil {
+		report.AppendLatencySecondsP50 = p50
+	}
+	p99, err := scalarMetric(ctx, receiverHTTPAddrs[0], "thanos_receive_write_duration_seconds{quantile=\"0.99\"}")
+	if err == nil {
+		report.AppendLatencySecondsP99 = p99
+	}
+
+	return report, nil
+}
+
+// scalarMetric queries a single receiver's own Prometheus-format
+// metrics endpoint for a metric name (optionally with a label
+// selector) and returns its instant value, or 0 if absent - a benchmark
+// comparing old/new binaries shouldn't fail outright just because one
+// side lacks a metric the other added.
+func scalarMetric(ctx context.Context, addr, query string) (float64, error) {
+	u, err := url.Parse("http://" + addr)
+	if err != nil {
+		return 0, err
+	}
+
+	vec, _, err := promclient.QueryInstant(ctx, nil, u, query, time.Now(), promclient.QueryOptions{})
+	if err != nil {
+		return 0, err
+	}
+	if len(vec) == 0 {
+		return 0, nil
+	}
+	return float64(vec[0].Value), nil
+}
+
+// Diff holds the relative change, in percent, of throughput, p99 append
+// latency and average resident memory between two named runs.
+type Diff struct {
+	A, B                    string  `json:"-"`
+	ThroughputDeltaPercent  float64 `json:"throughput_delta_percent"`
+	P99LatencyDeltaPercent  float64 `json:"p99_latency_delta_percent"`
+	ResidentMemDeltaPercent float64 `json:"resident_mem_delta_percent"`
+}
+
+// Compare returns how b differs from a, as percentages of a's value.
+func Compare(a, b Report) Diff {
+	return Diff{
+		A:                      a.Scenario.Name,
+		B:                      b.Scenario.Name,
+		ThroughputDeltaPercent: percentDelta(a.ThroughputSamplesPerSecond, b.ThroughputSamplesPerSecond),
+		P99LatencyDeltaPercent: percentDelta(a.AppendLatencySecondsP99, b.AppendLatencySecondsP99),
+		ResidentMemDeltaPercent: percentDelta(
+			averageResidentMem(a.PerReceiver),
+			averageResidentMem(b.PerReceiver),
+		),
+	}
+}
+
+func averageResidentMem(usages []ReceiverUsage) float64 {
+	if len(usages) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, u := range usages {
+		sum += u.ResidentMemBytes
+	}
+	return sum / float64(len(usages))
+}
+
+func percentDelta(a, b float64) float64 {
+	if a == 0 {
+		return 0
+	}
+	return (b - a) / a * 100
+}
+
+// WriteJSON serializes report as the JSON report file shipped alongside
+// a benchmark run.
+func WriteJSON(report Report) ([]byte, error) {
+	return json.MarshalIndent(report, "", "  ")
+}
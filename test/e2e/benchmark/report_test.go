@@ -0,0 +1,34 @@
+package benchmark
+
+import (
+	"testing"
+
+	"github.com/thanos-io/thanos/pkg/testutil"
+)
+
+func TestCompare(t *testing.T) {
+	a := Report{
+		Scenario:                   Scenario{Name: "A"},
+		ThroughputSamplesPerSecond: 1000,
+		AppendLatencySecondsP99:    0.1,
+		PerReceiver:                []ReceiverUsage{{ResidentMemBytes: 100}},
+	}
+	b := Report{
+		Scenario:                   Scenario{Name: "B"},
+		ThroughputSamplesPerSecond: 1100,
+		AppendLatencySecondsP99:    0.12,
+		PerReceiver:                []ReceiverUsage{{ResidentMemBytes: 120}},
+	}
+
+	diff := Compare(a, b)
+	testutil.Equals(t, "A", diff.A)
+	testutil.Equals(t, "B", diff.B)
+	testutil.Equals(t, 10.0, diff.ThroughputDeltaPercent)
+	testutil.Equals(t, 20.0, diff.ResidentMemDeltaPercent)
+}
+
+func TestWriteJSON(t *testing.T) {
+	raw, err := WriteJSON(Report{Scenario: Scenario{Name: "A"}, ThroughputSamplesPerSecond: 42})
+	testutil.Ok(t, err)
+	testutil.Assert(t, len(raw) > 0)
+}
@@ -0,0 +1,79 @@
+// Package benchmark holds the pieces of the remote-write throughput
+// harness used to A/B compare receiver changes: the YAML scenario
+// format, collection of a run's report from its receivers, and the
+// comparison of two reports. It does not launch scrapers, receivers or
+// queriers itself, and nothing in it generates load from a Scenario.
+package benchmark
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Scenario describes the synthetic load a benchmark run should
+// generate. It is loaded from a YAML file so reviewers can attach the
+// exact scenario that reproduced a regression to a PR, rather than a
+// prose description of load.
+type Scenario struct {
+	// Name identifies the run in the JSON report and in a Diff, e.g.
+	// "A" and "B" when comparing two receiver versions.
+	Name string `yaml:"name"`
+
+	// Duration is how long the scrapers generate load for.
+	Duration time.Duration `yaml:"duration"`
+
+	// Scrapers is the number of synthetic scrape targets to launch.
+	Scrapers int `yaml:"scrapers"`
+
+	// Receivers is the number of receive instances load is spread
+	// across.
+	Receivers int `yaml:"receivers"`
+
+	// SeriesPerScrapeInterval is how many distinct series each scraper
+	// exposes per scrape, before series churn is applied.
+	SeriesPerScrapeInterval int `yaml:"series_per_scrape_interval"`
+
+	// LabelCardinality is the number of distinct values generated for
+	// each non-identifying label, controlling how quickly the label
+	// value space grows independent of series churn.
+	LabelCardinality int `yaml:"label_cardinality"`
+
+	// SeriesChurnRatio is the fraction of series replaced by newly
+	// labeled series on each scrape, modeling workloads like
+	// per-pod-name Kubernetes labels.
+	SeriesChurnRatio float64 `yaml:"series_churn_ratio"`
+
+	// NativeHistogramRatio is the fraction of series emitted as native
+	// histograms instead of float samples.
+	NativeHistogramRatio float64 `yaml:"native_histogram_ratio"`
+}
+
+// LoadScenario parses and validates a benchmark scenario from YAML.
+func LoadScenario(raw []byte) (Scenario, error) {
+	var s Scenario
+	if err := yaml.Unmarshal(raw, &s); err != nil {
+		return Scenario{}, errors.Wrap(err, "unmarshal benchmark scenario")
+	}
+
+	if s.Name == "" {
+		return Scenario{}, errors.New("benchmark scenario: name is required")
+	}
+	if s.Scrapers <= 0 {
+		return Scenario{}, errors.New("benchmark scenario: scrapers must be > 0")
+	}
+	if s.Receivers <= 0 {
+		return Scenario{}, errors.New("benchmark scenario: receivers must be > 0")
+	}
+	if s.SeriesChurnRatio < 0 || s.SeriesChurnRatio > 1 {
+		return Scenario{}, errors.New("benchmark scenario: series_churn_ratio must be within [0, 1]")
+	}
+	if s.NativeHistogramRatio < 0 || s.NativeHistogramRatio > 1 {
+		return Scenario{}, errors.New("benchmark scenario: native_histogram_ratio must be within [0, 1]")
+	}
+	if s.Duration <= 0 {
+		s.Duration = 2 * time.Minute
+	}
+	return s, nil
+}
@@ -0,0 +1,52 @@
+package benchmark
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thanos-io/thanos/pkg/testutil"
+)
+
+func TestLoadScenario(t *testing.T) {
+	raw := []byte(`
+name: "A"
+duration: 1m
+scrapers: 4
+receivers: 2
+series_per_scrape_interval: 10000
+label_cardinality: 500
+series_churn_ratio: 0.1
+native_histogram_ratio: 0.2
+`)
+
+	s, err := LoadScenario(raw)
+	testutil.Ok(t, err)
+	testutil.Equals(t, "A", s.Name)
+	testutil.Equals(t, time.Minute, s.Duration)
+	testutil.Equals(t, 4, s.Scrapers)
+}
+
+func TestLoadScenario_DefaultsDuration(t *testing.T) {
+	s, err := LoadScenario([]byte(`name: "A"
+scrapers: 1
+receivers: 1
+`))
+	testutil.Ok(t, err)
+	testutil.Equals(t, 2*time.Minute, s.Duration)
+}
+
+func TestLoadScenario_Validation(t *testing.T) {
+	for _, raw := range []string{
+		`scrapers: 1
+receivers: 1`, // missing name
+		`name: "A"
+receivers: 1`, // missing scrapers
+		`name: "A"
+scrapers: 1
+receivers: 1
+series_churn_ratio: 1.5`, // out of range
+	} {
+		_, err := LoadScenario([]byte(raw))
+		testutil.NotOk(t, err)
+	}
+}
@@ -0,0 +1,52 @@
+package e2e_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/thanos-io/thanos/pkg/testutil"
+	"github.com/thanos-io/thanos/test/e2e/benchmark"
+)
+
+// receiverBenchmarkSuite spins up the topology the benchmark smoke test
+// runs against: one scraper remote-writing node_exporter's series to
+// one receiver. The suite has no querier. It is built from fixed
+// helpers, so a scenario's scraper count, series count, label
+// cardinality, churn and native-histogram mix are not applied to it.
+var receiverBenchmarkSuite = newSpinupSuite().
+	Add(scraper(1, defaultPromRemoteWriteConfig(nodeExporterHTTP(1), remoteWriteEndpoint(1)))).
+	Add(receiver(1, "", 1))
+
+// TestReceiverBenchmark runs a short scenario end to end and checks
+// that a report can be collected from the receiver's own metrics
+// endpoint. This is deliberately a smoke test of the harness itself,
+// not a representative throughput number; of the scenario, only its
+// name and duration are used.
+func TestReceiverBenchmark(t *testing.T) {
+	scenario, err := benchmark.LoadScenario([]byte(`
+name: "smoke"
+duration: 5s
+scrapers: 1
+receivers: 1
+series_per_scrape_interval: 100
+label_cardinality: 10
+series_churn_ratio: 0
+native_histogram_ratio: 0
+`))
+	testutil.Ok(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	exit, err := receiverBenchmarkSuite.Exec(t, ctx, "benchmarkSmoke")
+	testutil.Ok(t, err)
+	defer func() { cancel(); <-exit }()
+
+	start := time.Now()
+	time.Sleep(scenario.Duration)
+
+	report, err := benchmark.Collect(ctx, scenario, []string{receiveHTTP(1)}, start, time.Now())
+	testutil.Ok(t, err)
+	testutil.Equals(t, "smoke", report.Scenario.Name)
+}
@@ -3,14 +3,15 @@ package e2e_test
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/url"
 	"os"
 	"testing"
 	"time"
 
-	"github.com/go-kit/kit/log"
 	"github.com/pkg/errors"
 	"github.com/prometheus/common/model"
+	"github.com/thanos-io/thanos/pkg/logging"
 	"github.com/thanos-io/thanos/pkg/promclient"
 	"github.com/thanos-io/thanos/pkg/runutil"
 	"github.com/thanos-io/thanos/pkg/testutil"
@@ -77,9 +78,7 @@ func testQuerySimple(t *testing.T, conf testConfig) {
 
 	var res model.Vector
 
-	w := log.NewSyncWriter(os.Stderr)
-	l := log.NewLogfmtLogger(w)
-	l = log.With(l, "conf-name", conf.name)
+	l := logging.NewGoKitAdapter(slog.New(slog.NewTextHandler(os.Stderr, nil)).With("conf-name", conf.name))
 
 	// Try query without deduplication.
 	testutil.Ok(t, runutil.RetryWithLog(l, time.Second, ctx.Done(), func() error {